@@ -0,0 +1,95 @@
+// Package backend defines the storage interface used by kvdb and a registry
+// for plugging in concrete implementations, modeled on Tendermint's db
+// backend registry.
+package backend
+
+import (
+	"fmt"
+	"time"
+)
+
+// Record is a single key/value pair stored in a Backend.
+type Record struct {
+	Key   string
+	Value []byte
+}
+
+// Backend is the storage interface implemented by every concrete store
+// (LevelDB, BBolt, BadgerDB, ...). Implementations must be safe for
+// concurrent use.
+type Backend interface {
+	// Put writes or overwrites a batch of records.
+	Put(records []Record) error
+
+	// Get looks up a single record by exact key. It returns ErrNotFound if
+	// the key does not exist.
+	Get(key []byte) (Record, error)
+
+	// Delete removes the given keys. Missing keys are ignored.
+	Delete(keys [][]byte) error
+
+	// Search returns every record whose key starts with prefix.
+	Search(prefix string) ([]Record, error)
+
+	// Iterate walks records with keys in [start, end) in key order (or in
+	// reverse if reverse is true), calling fn for each one. Iteration stops
+	// early if fn returns false. A nil start or end means unbounded.
+	Iterate(start, end []byte, reverse bool, fn func(Record) bool) error
+
+	// Stats returns backend-specific diagnostic information.
+	Stats() map[string]string
+
+	Close() error
+}
+
+// ErrNotFound is returned by Get when the requested key does not exist.
+var ErrNotFound = fmt.Errorf("backend: key not found")
+
+// Options carries the parameters needed to open a Backend. Dir is the base
+// path of the stored data; the built-in embedded backends append their own
+// extension to it (".badger", ".bbolt", ".ldb", ".bleve"). Addr and TTL are
+// only used by remote backends (e.g. memcached).
+type Options struct {
+	Name string
+	Dir  string
+
+	// Addr is the "host:port" of a remote store, used by network backends.
+	Addr string
+
+	// TTL is the expiration applied to records written to a remote store.
+	// Zero means the store's default (commonly "never expires").
+	TTL time.Duration
+}
+
+// Factory creates a Backend from Options. Concrete backends register a
+// Factory under a name via Register.
+type Factory func(opts Options) (Backend, error)
+
+var factories = map[string]Factory{}
+
+// Register makes a backend factory available under name. It panics if name
+// is already registered, matching the common init()-time registry pattern.
+func Register(name string, factory Factory) {
+	if _, exists := factories[name]; exists {
+		panic(fmt.Sprintf("backend: factory already registered under name %q", name))
+	}
+	factories[name] = factory
+}
+
+// New opens a Backend by name using the factory registered for it.
+func New(name string, opts Options) (Backend, error) {
+	factory, ok := factories[name]
+	if !ok {
+		return nil, fmt.Errorf("backend: unknown backend type %q", name)
+	}
+	return factory(opts)
+}
+
+// Registered returns the names of all currently registered backends.
+func Registered() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	return names
+}
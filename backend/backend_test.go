@@ -0,0 +1,32 @@
+package backend
+
+import "testing"
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Register did not panic on a duplicate name")
+		}
+	}()
+	Register("dummy", func(opts Options) (Backend, error) {
+		return NewDummyBackend(), nil
+	})
+}
+
+func TestNewUnknownBackend(t *testing.T) {
+	if _, err := New("does-not-exist", Options{}); err == nil {
+		t.Fatal("New returned no error for an unregistered backend name")
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	found := false
+	for _, name := range Registered() {
+		if name == "dummy" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Registered() = %v, want it to include %q", Registered(), "dummy")
+	}
+}
@@ -0,0 +1,153 @@
+package backend
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v3"
+)
+
+func init() {
+	Register("badgerdb", func(opts Options) (Backend, error) {
+		return NewBadgerDBBackend(opts.Dir + ".badger")
+	})
+}
+
+type BadgerDBBackend struct {
+	db *badger.DB
+}
+
+func NewBadgerDBBackend(filename string) (*BadgerDBBackend, error) {
+	opts := badger.DefaultOptions(filename).
+		WithLoggingLevel(badger.WARNING)
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &BadgerDBBackend{
+		db: db,
+	}, nil
+}
+
+func (b *BadgerDBBackend) Close() error {
+	return b.db.Close()
+}
+
+func (b *BadgerDBBackend) Put(records []Record) error {
+	wb := b.db.NewWriteBatch()
+	for _, r := range records {
+		if err := wb.Set([]byte(r.Key), r.Value); err != nil {
+			return err
+		}
+	}
+
+	return wb.Flush()
+}
+
+func (b *BadgerDBBackend) Get(key []byte) (Record, error) {
+	var rec Record
+	err := b.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		rec = Record{Key: string(item.Key()), Value: value}
+		return nil
+	})
+	if err != nil {
+		return Record{}, err
+	}
+	return rec, nil
+}
+
+func (b *BadgerDBBackend) Delete(keys [][]byte) error {
+	wb := b.db.NewWriteBatch()
+	for _, k := range keys {
+		if err := wb.Delete(k); err != nil {
+			return err
+		}
+	}
+	return wb.Flush()
+}
+
+func (b *BadgerDBBackend) Search(prefix string) ([]Record, error) {
+	var records []Record
+	b.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		bprefix := []byte(prefix)
+		for it.Seek(bprefix); it.ValidForPrefix(bprefix); it.Next() {
+			item := it.Item()
+			key := item.Key()
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			records = append(records, Record{
+				Key:   string(key),
+				Value: value,
+			})
+		}
+		return nil
+	})
+	return records, nil
+}
+
+func (b *BadgerDBBackend) Iterate(start, end []byte, reverse bool, fn func(Record) bool) error {
+	return b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = reverse
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		seek := start
+		if reverse {
+			seek = end
+		}
+		if seek != nil {
+			it.Seek(seek)
+		} else {
+			it.Rewind()
+		}
+
+		for ; it.Valid(); it.Next() {
+			item := it.Item()
+			key := item.Key()
+			if !reverse && end != nil && bytes.Compare(key, end) >= 0 {
+				break
+			}
+			if reverse && start != nil && bytes.Compare(key, start) < 0 {
+				break
+			}
+			if reverse && end != nil && bytes.Compare(key, end) >= 0 {
+				continue
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if !fn(Record{Key: string(key), Value: value}) {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+func (b *BadgerDBBackend) Stats() map[string]string {
+	lsm, vlog := b.db.Size()
+	return map[string]string{
+		"type":      "badgerdb",
+		"lsm_size":  fmt.Sprintf("%d", lsm),
+		"vlog_size": fmt.Sprintf("%d", vlog),
+	}
+}
@@ -0,0 +1,157 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+)
+
+var bucketName = []byte("bucket")
+
+func init() {
+	Register("bbolt", func(opts Options) (Backend, error) {
+		return NewBBoltBackend(opts.Dir + ".bbolt")
+	})
+}
+
+type BBoltBackend struct {
+	db *bbolt.DB
+}
+
+func NewBBoltBackend(filename string) (*BBoltBackend, error) {
+	db, err := bbolt.Open(filename, 0666, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BBoltBackend{
+		db: db,
+	}, nil
+}
+
+func (b *BBoltBackend) Put(records []Record) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(bucketName)
+		if err != nil {
+			return err
+		}
+		for _, r := range records {
+			if err := bucket.Put([]byte(r.Key), r.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *BBoltBackend) Get(key []byte) (Record, error) {
+	var rec Record
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(bucketName)
+		if bucket == nil {
+			return ErrNotFound
+		}
+		value := bucket.Get(key)
+		if value == nil {
+			return ErrNotFound
+		}
+		rec = Record{Key: string(key), Value: copyBytes(value)}
+		return nil
+	})
+	if err != nil {
+		return Record{}, err
+	}
+	return rec, nil
+}
+
+func (b *BBoltBackend) Delete(keys [][]byte) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(bucketName)
+		if bucket == nil {
+			return nil
+		}
+		for _, k := range keys {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *BBoltBackend) Search(prefix string) ([]Record, error) {
+	var records []Record
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		// Assume bucket exists and has keys
+		c := tx.Bucket(bucketName).Cursor()
+
+		bprefix := []byte(prefix)
+		for k, v := c.Seek(bprefix); k != nil && bytes.HasPrefix(k, bprefix); k, v = c.Next() {
+			records = append(records, Record{
+				Key:   string(k),
+				Value: copyBytes(v),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (b *BBoltBackend) Iterate(start, end []byte, reverse bool, fn func(Record) bool) error {
+	return b.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(bucketName)
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+
+		var k, v []byte
+		var next func() ([]byte, []byte)
+		if reverse {
+			if end != nil {
+				c.Seek(end)
+				k, v = c.Prev()
+			} else {
+				k, v = c.Last()
+			}
+			next = c.Prev
+		} else {
+			if start != nil {
+				k, v = c.Seek(start)
+			} else {
+				k, v = c.First()
+			}
+			next = c.Next
+		}
+
+		for ; k != nil; k, v = next() {
+			if !reverse && end != nil && bytes.Compare(k, end) >= 0 {
+				break
+			}
+			if reverse && start != nil && bytes.Compare(k, start) < 0 {
+				break
+			}
+			if !fn(Record{Key: string(k), Value: copyBytes(v)}) {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+func (b *BBoltBackend) Stats() map[string]string {
+	stats := b.db.Stats()
+	return map[string]string{
+		"type":      "bbolt",
+		"tx_count":  fmt.Sprintf("%d", stats.TxN),
+		"free_page": fmt.Sprintf("%d", stats.FreePageN),
+	}
+}
+
+func (b *BBoltBackend) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,84 @@
+package backend
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newTestBBoltBackend(t *testing.T) *BBoltBackend {
+	t.Helper()
+	b, err := NewBBoltBackend(filepath.Join(t.TempDir(), "test.bbolt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func TestBBoltBackendRoundTrip(t *testing.T) {
+	b := newTestBBoltBackend(t)
+
+	records := []Record{
+		{Key: "apple", Value: []byte("1")},
+		{Key: "apricot", Value: []byte("2")},
+		{Key: "banana", Value: []byte("3")},
+	}
+	if err := b.Put(records); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := b.Get([]byte("apple"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rec.Value) != "1" {
+		t.Fatalf("Get(apple).Value = %q, want %q", rec.Value, "1")
+	}
+
+	if _, err := b.Get([]byte("missing")); err != ErrNotFound {
+		t.Fatalf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+
+	got, err := b.Search("ap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Search(ap) returned %d records, want 2", len(got))
+	}
+
+	var iterated []string
+	err = b.Iterate(nil, nil, false, func(r Record) bool {
+		iterated = append(iterated, r.Key)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"apple", "apricot", "banana"}; !equalStrings(iterated, want) {
+		t.Fatalf("Iterate visited %v, want %v", iterated, want)
+	}
+
+	if err := b.Delete([][]byte{[]byte("apple")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Get([]byte("apple")); err != ErrNotFound {
+		t.Fatalf("Get(apple) after Delete error = %v, want ErrNotFound", err)
+	}
+
+	if stats := b.Stats(); stats["type"] != "bbolt" {
+		t.Fatalf(`Stats()["type"] = %q, want "bbolt"`, stats["type"])
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
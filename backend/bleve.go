@@ -0,0 +1,141 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+func init() {
+	Register("bleve", func(opts Options) (Backend, error) {
+		return NewBleveBackend(opts.Dir + ".bleve")
+	})
+}
+
+// Queryable is implemented by backends that support rich query-string
+// searches (substring, fuzzy, phrase) beyond simple prefix matching.
+type Queryable interface {
+	Query(q string) ([]Record, error)
+}
+
+// BleveBackend indexes every Record in a Bleve full-text index, with the key
+// stored as an unanalyzed keyword field and the value tokenized, so it
+// supports substring, fuzzy, and phrase queries instead of just prefix
+// lookups.
+type BleveBackend struct {
+	index bleve.Index
+}
+
+func NewBleveBackend(filename string) (*BleveBackend, error) {
+	index, err := bleve.Open(filename)
+	if err == nil {
+		return &BleveBackend{index: index}, nil
+	}
+
+	mapping := bleve.NewIndexMapping()
+	docMapping := bleve.NewDocumentMapping()
+
+	keyField := bleve.NewTextFieldMapping()
+	keyField.Analyzer = "keyword"
+	docMapping.AddFieldMappingsAt("key", keyField)
+
+	valueField := bleve.NewTextFieldMapping()
+	docMapping.AddFieldMappingsAt("value", valueField)
+
+	mapping.DefaultMapping = docMapping
+
+	index, err = bleve.New(filename, mapping)
+	if err != nil {
+		return nil, err
+	}
+	return &BleveBackend{index: index}, nil
+}
+
+type bleveDoc struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func (b *BleveBackend) Put(records []Record) error {
+	batch := b.index.NewBatch()
+	for _, r := range records {
+		if err := batch.Index(r.Key, bleveDoc{Key: r.Key, Value: string(r.Value)}); err != nil {
+			return err
+		}
+	}
+	return b.index.Batch(batch)
+}
+
+func (b *BleveBackend) Get(key []byte) (Record, error) {
+	records, err := b.search(bleve.NewDocIDQuery([]string{string(key)}), 1)
+	if err != nil {
+		return Record{}, err
+	}
+	if len(records) == 0 {
+		return Record{}, ErrNotFound
+	}
+	return records[0], nil
+}
+
+func (b *BleveBackend) Delete(keys [][]byte) error {
+	batch := b.index.NewBatch()
+	for _, k := range keys {
+		batch.Delete(string(k))
+	}
+	return b.index.Batch(batch)
+}
+
+// Search returns the records whose key starts with prefix, at most 1<<20 of
+// them.
+func (b *BleveBackend) Search(prefix string) ([]Record, error) {
+	q := bleve.NewPrefixQuery(prefix)
+	q.SetField("key")
+	return b.search(q, 0)
+}
+
+// Query runs q as a Bleve query string against the indexed value (and key)
+// fields, supporting substring (wildcard), fuzzy, and phrase queries -
+// things plain prefix Search cannot express.
+func (b *BleveBackend) Query(q string) ([]Record, error) {
+	return b.search(bleve.NewQueryStringQuery(q), 0)
+}
+
+func (b *BleveBackend) search(q query.Query, limit int) ([]Record, error) {
+	req := bleve.NewSearchRequest(q)
+	req.Fields = []string{"key", "value"}
+	if limit > 0 {
+		req.Size = limit
+	} else {
+		req.Size = 1 << 20
+	}
+
+	res, err := b.index.Search(req)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(res.Hits))
+	for _, hit := range res.Hits {
+		key, _ := hit.Fields["key"].(string)
+		value, _ := hit.Fields["value"].(string)
+		records = append(records, Record{Key: key, Value: []byte(value)})
+	}
+	return records, nil
+}
+
+func (b *BleveBackend) Iterate(start, end []byte, reverse bool, fn func(Record) bool) error {
+	return fmt.Errorf("bleve: Iterate is not supported, use Search or Query instead")
+}
+
+func (b *BleveBackend) Stats() map[string]string {
+	count, _ := b.index.DocCount()
+	return map[string]string{
+		"type":      "bleve",
+		"doc_count": fmt.Sprintf("%d", count),
+	}
+}
+
+func (b *BleveBackend) Close() error {
+	return b.index.Close()
+}
@@ -0,0 +1,43 @@
+package backend
+
+func init() {
+	Register("dummy", func(opts Options) (Backend, error) {
+		return NewDummyBackend(), nil
+	})
+}
+
+// DummyBackend discards everything it is given. It is useful for
+// benchmarking the parser/writer pipeline without storage overhead.
+type DummyBackend struct{}
+
+func NewDummyBackend() *DummyBackend {
+	return &DummyBackend{}
+}
+
+func (b *DummyBackend) Put(records []Record) error {
+	return nil
+}
+
+func (b *DummyBackend) Get(key []byte) (Record, error) {
+	return Record{}, ErrNotFound
+}
+
+func (b *DummyBackend) Delete(keys [][]byte) error {
+	return nil
+}
+
+func (b *DummyBackend) Search(prefix string) ([]Record, error) {
+	return nil, nil
+}
+
+func (b *DummyBackend) Iterate(start, end []byte, reverse bool, fn func(Record) bool) error {
+	return nil
+}
+
+func (b *DummyBackend) Stats() map[string]string {
+	return map[string]string{"type": "dummy"}
+}
+
+func (b *DummyBackend) Close() error {
+	return nil
+}
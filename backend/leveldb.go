@@ -0,0 +1,102 @@
+package backend
+
+import (
+	"errors"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+func init() {
+	Register("leveldb", func(opts Options) (Backend, error) {
+		return NewLevelDBBackend(opts.Dir + ".ldb")
+	})
+}
+
+type LevelDBBackend struct {
+	db *leveldb.DB
+}
+
+func NewLevelDBBackend(filename string) (*LevelDBBackend, error) {
+	db, err := leveldb.OpenFile(filename, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LevelDBBackend{
+		db: db,
+	}, nil
+}
+
+// best batch size: 50000
+func (b *LevelDBBackend) Put(records []Record) error {
+	batch := leveldb.Batch{}
+	for _, r := range records {
+		batch.Put([]byte(r.Key), r.Value)
+	}
+	return b.db.Write(&batch, nil) // write options do not change anything
+}
+
+func (b *LevelDBBackend) Get(key []byte) (Record, error) {
+	value, err := b.db.Get(key, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return Record{}, ErrNotFound
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	return Record{Key: string(key), Value: value}, nil
+}
+
+func (b *LevelDBBackend) Delete(keys [][]byte) error {
+	batch := leveldb.Batch{}
+	for _, k := range keys {
+		batch.Delete(k)
+	}
+	return b.db.Write(&batch, nil)
+}
+
+func (b *LevelDBBackend) Search(prefix string) ([]Record, error) {
+	var records []Record
+	iter := b.db.NewIterator(util.BytesPrefix([]byte(prefix)), nil)
+	for iter.Next() {
+		records = append(records, Record{
+			Key:   string(iter.Key()),
+			Value: copyBytes(iter.Value()),
+		})
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (b *LevelDBBackend) Iterate(start, end []byte, reverse bool, fn func(Record) bool) error {
+	iter := b.db.NewIterator(&util.Range{Start: start, Limit: end}, nil)
+	defer iter.Release()
+
+	advance := iter.Next
+	ok := iter.First()
+	if reverse {
+		advance = iter.Prev
+		ok = iter.Last()
+	}
+	for ; ok; ok = advance() {
+		rec := Record{
+			Key:   string(iter.Key()),
+			Value: copyBytes(iter.Value()),
+		}
+		if !fn(rec) {
+			break
+		}
+	}
+	return iter.Error()
+}
+
+func (b *LevelDBBackend) Stats() map[string]string {
+	return map[string]string{"type": "leveldb"}
+}
+
+func (b *LevelDBBackend) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,197 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+func init() {
+	Register("memcached", func(opts Options) (Backend, error) {
+		if opts.Addr == "" {
+			return nil, fmt.Errorf("memcached backend requires a host:port address (Options.Addr)")
+		}
+		return NewMemcachedBackend(opts.Addr, opts.TTL)
+	})
+}
+
+// indexBucketChars is how many leading characters of a key select its
+// prefix-index bucket. A Search prefix shorter than this reads the bucket
+// named by the whole prefix, which holds only keys that short themselves, so
+// it does not find longer keys; an empty prefix finds no records.
+const indexBucketChars = 1
+
+// MemcachedBackend stores records in a remote memcached cluster (or any
+// Couchbase-style store speaking the memcached protocol), so kvdb can point
+// at shared, remote KV storage instead of an embedded file.
+//
+// memcached has no range scan, so Search is backed by a secondary index: for
+// every key written, its first indexBucketChars are used as a bucket key
+// whose value is the newline-joined set of keys sharing that bucket
+// (memcached keys cannot contain whitespace or control characters, so Set
+// rejects a key with a newline before it is indexed). This trades write
+// amplification and a read-modify-write race between separate clients
+// (other processes, or other MemcachedBackend values) updating the same
+// bucket (last writer wins, so an interleaving can drop an index entry) for
+// the ability to do prefix search at all against a store with no native
+// scan. Concurrent Puts on one MemcachedBackend update the index under a
+// mutex and do not race with each other. A companion embedded backend used
+// purely for the index would avoid the race, at the cost of requiring two
+// stores.
+type MemcachedBackend struct {
+	client *memcache.Client
+	ttl    int32
+
+	indexMu sync.Mutex
+}
+
+func NewMemcachedBackend(addr string, ttl time.Duration) (*MemcachedBackend, error) {
+	client := memcache.New(addr)
+	if err := client.Ping(); err != nil {
+		return nil, fmt.Errorf("memcached: %w", err)
+	}
+	return &MemcachedBackend{
+		client: client,
+		ttl:    int32(ttl.Seconds()),
+	}, nil
+}
+
+func (b *MemcachedBackend) Put(records []Record) error {
+	newKeysByBucket := map[string][]string{}
+	for _, r := range records {
+		item := &memcache.Item{
+			Key:        r.Key,
+			Value:      r.Value,
+			Expiration: b.ttl,
+		}
+		if err := b.client.Set(item); err != nil {
+			return fmt.Errorf("memcached: set %q: %w", r.Key, err)
+		}
+		bucket := indexBucket(r.Key)
+		newKeysByBucket[bucket] = append(newKeysByBucket[bucket], r.Key)
+	}
+	for bucket, keys := range newKeysByBucket {
+		if err := b.addToIndex(bucket, keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *MemcachedBackend) Get(key []byte) (Record, error) {
+	item, err := b.client.Get(string(key))
+	if errors.Is(err, memcache.ErrCacheMiss) {
+		return Record{}, ErrNotFound
+	}
+	if err != nil {
+		return Record{}, fmt.Errorf("memcached: get %q: %w", key, err)
+	}
+	return Record{Key: string(key), Value: item.Value}, nil
+}
+
+func (b *MemcachedBackend) Delete(keys [][]byte) error {
+	for _, k := range keys {
+		if err := b.client.Delete(string(k)); err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
+			return fmt.Errorf("memcached: delete %q: %w", k, err)
+		}
+	}
+	// Index entries for deleted keys are left in place; Search filters out
+	// misses when it fetches the indexed keys, so this only costs a handful
+	// of wasted lookups rather than incorrect results.
+	return nil
+}
+
+func (b *MemcachedBackend) Search(prefix string) ([]Record, error) {
+	bucket := indexBucket(prefix)
+	keys, err := b.readIndex(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []string
+	for _, k := range keys {
+		if strings.HasPrefix(k, prefix) {
+			matching = append(matching, k)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, nil
+	}
+
+	items, err := b.client.GetMulti(matching)
+	if err != nil {
+		return nil, fmt.Errorf("memcached: search %q: %w", prefix, err)
+	}
+	records := make([]Record, 0, len(items))
+	for key, item := range items {
+		records = append(records, Record{Key: key, Value: item.Value})
+	}
+	return records, nil
+}
+
+func (b *MemcachedBackend) Iterate(start, end []byte, reverse bool, fn func(Record) bool) error {
+	return fmt.Errorf("memcached: Iterate is not supported, use Search instead")
+}
+
+func (b *MemcachedBackend) Stats() map[string]string {
+	return map[string]string{"type": "memcached"}
+}
+
+func (b *MemcachedBackend) Close() error {
+	return nil
+}
+
+// indexBucket returns the prefix-index bucket key for s.
+func indexBucket(s string) string {
+	n := indexBucketChars
+	if len(s) < n {
+		n = len(s)
+	}
+	return "kvdb:index:" + s[:n]
+}
+
+// readIndex returns the set of keys currently recorded under bucket.
+func (b *MemcachedBackend) readIndex(bucket string) ([]string, error) {
+	item, err := b.client.Get(bucket)
+	if errors.Is(err, memcache.ErrCacheMiss) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("memcached: read index %q: %w", bucket, err)
+	}
+	if len(item.Value) == 0 {
+		return nil, nil
+	}
+	return strings.Split(string(item.Value), "\n"), nil
+}
+
+// addToIndex merges newKeys into bucket's existing key set and writes it
+// back. See the MemcachedBackend doc comment for the race this accepts.
+func (b *MemcachedBackend) addToIndex(bucket string, newKeys []string) error {
+	b.indexMu.Lock()
+	defer b.indexMu.Unlock()
+
+	existing, err := b.readIndex(bucket)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(existing)+len(newKeys))
+	merged := make([]string, 0, len(existing)+len(newKeys))
+	for _, k := range append(existing, newKeys...) {
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		merged = append(merged, k)
+	}
+
+	return b.client.Set(&memcache.Item{
+		Key:   bucket,
+		Value: []byte(strings.Join(merged, "\n")),
+	})
+}
@@ -0,0 +1,54 @@
+package load
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	bzip2Magic = []byte("BZh")
+)
+
+// decompress peeks at the first bytes of r and, if they match a known
+// compression magic number, wraps r in the matching decompressor. Plain
+// (uncompressed) input is returned unchanged.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	switch {
+	case hasPrefix(magic, gzipMagic):
+		return gzip.NewReader(br)
+	case hasPrefix(magic, zstdMagic):
+		dec, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	case hasPrefix(magic, bzip2Magic):
+		return bzip2.NewReader(br), nil
+	default:
+		return br, nil
+	}
+}
+
+func hasPrefix(data, prefix []byte) bool {
+	if len(data) < len(prefix) {
+		return false
+	}
+	for i, b := range prefix {
+		if data[i] != b {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package load
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/zerodivisi0n/kvdb/backend"
+)
+
+// loadDelimited implements the CSV/TSV pipeline. Parsing a delimited stream
+// is inherently sequential (rows share reader state), so a single reader
+// goroutine resolves the header and feeds a pool of writer goroutines that
+// batch records into db.Put calls, same as loadLines.
+func loadDelimited(ctx context.Context, db backend.Backend, r io.Reader, opts LoadOptions) (Stats, error) {
+	cr := csv.NewReader(r)
+	if opts.Format == FormatTSV {
+		cr.Comma = '\t'
+	}
+
+	header, err := cr.Read()
+	if err != nil {
+		return Stats{}, fmt.Errorf("load: reading header: %w", err)
+	}
+	keyCol, err := resolveColumn(header, opts.KeyField)
+	if err != nil {
+		return Stats{}, err
+	}
+	valueCol, err := resolveColumn(header, opts.ValueField)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	start := time.Now()
+	writerCh := make(chan backend.Record)
+	writersWg, totalRecords := startWriters(db, opts, writerCh)
+
+	var readErr error
+loop:
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			readErr = err
+			break
+		}
+		if keyCol >= len(row) || valueCol >= len(row) {
+			continue
+		}
+		key, value := row[keyCol], row[valueCol]
+		if key == "" || value == "" {
+			continue
+		}
+		if opts.ReverseKeys {
+			key = reverseString(key)
+		}
+		select {
+		case <-ctx.Done():
+			readErr = ctx.Err()
+			break loop
+		case writerCh <- backend.Record{Key: key, Value: []byte(value)}:
+		}
+	}
+
+	close(writerCh)
+	writersWg.Wait()
+
+	return Stats{Records: *totalRecords, Elapsed: time.Since(start)}, readErr
+}
+
+// resolveColumn finds field's index in header, falling back to treating
+// field itself as a 0-based column index.
+func resolveColumn(header []string, field string) (int, error) {
+	for i, name := range header {
+		if name == field {
+			return i, nil
+		}
+	}
+	if idx, err := strconv.Atoi(field); err == nil && idx >= 0 {
+		return idx, nil
+	}
+	return 0, fmt.Errorf("load: column %q not found in header %v", field, header)
+}
@@ -0,0 +1,66 @@
+package load
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+// Extractor pulls a key/value pair out of one raw record (one JSONL line). ok
+// is false when the fields were missing or empty, in which case the record is
+// skipped.
+type Extractor func(line []byte) (key, value []byte, ok bool)
+
+// newExtractor builds the Extractor for opts.Format using opts.KeyField and
+// opts.ValueField, which are dot-separated JSON paths for jsonl (e.g.
+// "meta.name"). For csv/tsv it returns a nil Extractor.
+func newExtractor(opts LoadOptions) (Extractor, error) {
+	keyPath := strings.Split(opts.KeyField, ".")
+	valuePath := strings.Split(opts.ValueField, ".")
+
+	switch opts.Format {
+	case FormatJSONL:
+		return jsonExtractor(keyPath, valuePath), nil
+	case FormatCSV, FormatTSV:
+		// CSV/TSV rows are handled by loadDelimited, which resolves
+		// opts.KeyField/ValueField against the header directly.
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("load: unsupported format %q", opts.Format)
+	}
+}
+
+func jsonExtractor(keyPath, valuePath []string) Extractor {
+	return func(line []byte) (key, value []byte, ok bool) {
+		keyStr, err := jsonparser.GetString(line, keyPath...)
+		if err != nil {
+			return nil, nil, false
+		}
+		val, _, _, err := jsonparser.Get(line, valuePath...)
+		if err != nil {
+			return nil, nil, false
+		}
+		if len(keyStr) == 0 || len(val) == 0 {
+			return nil, nil, false
+		}
+		return []byte(keyStr), val, true
+	}
+}
+
+// digPath walks doc following the dot-separated path, used by loadMsgpack to
+// resolve KeyField/ValueField against a decoded record.
+func digPath(doc map[string]interface{}, path []string) (interface{}, bool) {
+	var cur interface{} = doc
+	for _, p := range path {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[p]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
@@ -0,0 +1,192 @@
+// Package load implements the parser/writer pipeline that feeds records
+// into a backend.Backend, decoupled from the CLI so it can be reused by
+// tests and the serve daemon.
+package load
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zerodivisi0n/kvdb/backend"
+)
+
+// Format identifies the encoding of the records in the input stream.
+type Format string
+
+const (
+	FormatJSONL   Format = "jsonl"
+	FormatCSV     Format = "csv"
+	FormatTSV     Format = "tsv"
+	FormatMsgpack Format = "msgpack"
+)
+
+// LoadOptions configures Load. Compression (plain, gzip, zstd, bzip2) is
+// always auto-detected from the stream's magic bytes, so it has no option.
+type LoadOptions struct {
+	// Format is the record encoding. Defaults to FormatJSONL.
+	Format Format
+
+	// KeyField and ValueField select the record's key and value. For
+	// FormatJSONL/FormatMsgpack they are dot-separated JSON paths (default
+	// "name"/"value"); for FormatCSV/FormatTSV they are header names or
+	// 0-based column indexes.
+	KeyField   string
+	ValueField string
+
+	// ReverseKeys stores each key reversed, for backends relying on the
+	// suffix-search trick (see runQuery in the CLI).
+	ReverseKeys bool
+
+	// BatchSize is the number of records buffered before a Put call.
+	BatchSize int
+
+	// Parsers and Writers size the worker pools; they default to 12 and 4.
+	Parsers int
+	Writers int
+}
+
+func (o *LoadOptions) setDefaults() {
+	if o.Format == "" {
+		o.Format = FormatJSONL
+	}
+	if o.KeyField == "" {
+		o.KeyField = "name"
+	}
+	if o.ValueField == "" {
+		o.ValueField = "value"
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 5000
+	}
+	if o.Parsers <= 0 {
+		o.Parsers = 12
+	}
+	if o.Writers <= 0 {
+		o.Writers = 4
+	}
+}
+
+// Stats summarizes a completed Load call.
+type Stats struct {
+	Records int64
+	Elapsed time.Duration
+}
+
+// Load decompresses r (auto-detecting gzip/zstd/bzip2/plain), parses it
+// according to opts.Format, and writes the resulting records into db in
+// batches of opts.BatchSize, using a pool of parser and writer goroutines.
+// It returns once ctx is canceled or the input is exhausted.
+func Load(ctx context.Context, db backend.Backend, r io.Reader, opts LoadOptions) (Stats, error) {
+	opts.setDefaults()
+
+	dr, err := decompress(r)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	switch opts.Format {
+	case FormatCSV, FormatTSV:
+		return loadDelimited(ctx, db, dr, opts)
+	case FormatMsgpack:
+		return loadMsgpack(ctx, db, dr, opts)
+	default:
+		extract, err := newExtractor(opts)
+		if err != nil {
+			return Stats{}, err
+		}
+		return loadLines(ctx, db, dr, opts, extract)
+	}
+}
+
+// loadLines implements the line-oriented (JSONL) pipeline: a pool of parser
+// goroutines extracts a Record from each line and a pool of writer
+// goroutines batches records into db.Put calls.
+func loadLines(ctx context.Context, db backend.Backend, r io.Reader, opts LoadOptions, extract Extractor) (Stats, error) {
+	start := time.Now()
+	parserCh := make(chan []byte)
+	writerCh := make(chan backend.Record)
+
+	var parsersWg sync.WaitGroup
+	for i := 0; i < opts.Parsers; i++ {
+		parsersWg.Add(1)
+		go func() {
+			defer parsersWg.Done()
+			for data := range parserCh {
+				key, value, ok := extract(data)
+				if !ok {
+					continue
+				}
+				storedKey := string(key)
+				if opts.ReverseKeys {
+					storedKey = reverseString(storedKey)
+				}
+				writerCh <- backend.Record{
+					Key:   storedKey,
+					Value: copyBytes(value),
+				}
+			}
+		}()
+	}
+
+	writersWg, totalRecords := startWriters(db, opts, writerCh)
+
+	scanner := bufio.NewScanner(r)
+	var scanErr error
+loop:
+	for scanner.Scan() {
+		select {
+		case <-ctx.Done():
+			scanErr = ctx.Err()
+			break loop
+		case parserCh <- copyBytes(scanner.Bytes()):
+		}
+	}
+	if scanErr == nil {
+		scanErr = scanner.Err()
+	}
+
+	close(parserCh)
+	parsersWg.Wait()
+
+	close(writerCh)
+	writersWg.Wait()
+
+	return Stats{Records: *totalRecords, Elapsed: time.Since(start)}, scanErr
+}
+
+// startWriters spawns opts.Writers goroutines that batch records from
+// writerCh into db.Put calls of opts.BatchSize, shared by loadLines and
+// loadDelimited. It returns a WaitGroup to join on and a pointer to the
+// running record count.
+func startWriters(db backend.Backend, opts LoadOptions, writerCh <-chan backend.Record) (*sync.WaitGroup, *int64) {
+	var writersWg sync.WaitGroup
+	var totalRecords int64
+	for i := 0; i < opts.Writers; i++ {
+		writersWg.Add(1)
+		go func() {
+			defer writersWg.Done()
+			records := make([]backend.Record, 0, opts.BatchSize)
+			for r := range writerCh {
+				records = append(records, r)
+				if len(records) == opts.BatchSize {
+					if err := db.Put(records); err != nil {
+						log.Printf("Failed to put records: %v", err)
+					}
+					records = records[:0]
+				}
+				atomic.AddInt64(&totalRecords, 1)
+			}
+			if len(records) > 0 {
+				if err := db.Put(records); err != nil {
+					log.Printf("Failed to put records: %v", err)
+				}
+			}
+		}()
+	}
+	return &writersWg, &totalRecords
+}
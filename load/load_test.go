@@ -0,0 +1,162 @@
+package load
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/zerodivisi0n/kvdb/backend"
+)
+
+// memBackend is a minimal backend.Backend that keeps every Put'd record in
+// memory, for asserting on what Load actually wrote.
+type memBackend struct {
+	mu      sync.Mutex
+	records map[string][]byte
+}
+
+func newMemBackend() *memBackend {
+	return &memBackend{records: map[string][]byte{}}
+}
+
+func (b *memBackend) Put(records []backend.Record) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, r := range records {
+		b.records[r.Key] = r.Value
+	}
+	return nil
+}
+
+func (b *memBackend) Get(key []byte) (backend.Record, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	v, ok := b.records[string(key)]
+	if !ok {
+		return backend.Record{}, backend.ErrNotFound
+	}
+	return backend.Record{Key: string(key), Value: v}, nil
+}
+
+func (b *memBackend) Delete(keys [][]byte) error { return nil }
+
+func (b *memBackend) Search(prefix string) ([]backend.Record, error) { return nil, nil }
+
+func (b *memBackend) Iterate(start, end []byte, reverse bool, fn func(backend.Record) bool) error {
+	return nil
+}
+
+func (b *memBackend) Stats() map[string]string { return nil }
+
+func (b *memBackend) Close() error { return nil }
+
+func (b *memBackend) keys() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	keys := make([]string, 0, len(b.records))
+	for k := range b.records {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestLoadJSONL(t *testing.T) {
+	input := strings.NewReader(`{"name":"alice","value":"30"}
+{"name":"bob","value":"25"}
+`)
+	db := newMemBackend()
+	stats, err := Load(context.Background(), db, input, LoadOptions{Format: FormatJSONL, BatchSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Records != 2 {
+		t.Fatalf("stats.Records = %d, want 2", stats.Records)
+	}
+	if got, want := db.keys(), []string{"alice", "bob"}; !equal(got, want) {
+		t.Fatalf("keys = %v, want %v", got, want)
+	}
+	if v, _ := db.Get([]byte("alice")); string(v.Value) != "30" {
+		t.Fatalf("alice value = %q, want 30", v.Value)
+	}
+}
+
+func TestLoadCSV(t *testing.T) {
+	input := strings.NewReader("name,value\nalice,30\nbob,25\n")
+	db := newMemBackend()
+	stats, err := Load(context.Background(), db, input, LoadOptions{Format: FormatCSV, BatchSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Records != 2 {
+		t.Fatalf("stats.Records = %d, want 2", stats.Records)
+	}
+	if got, want := db.keys(), []string{"alice", "bob"}; !equal(got, want) {
+		t.Fatalf("keys = %v, want %v", got, want)
+	}
+}
+
+func TestLoadTSV(t *testing.T) {
+	input := strings.NewReader("name\tvalue\nalice\t30\nbob\t25\n")
+	db := newMemBackend()
+	stats, err := Load(context.Background(), db, input, LoadOptions{Format: FormatTSV, BatchSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Records != 2 {
+		t.Fatalf("stats.Records = %d, want 2", stats.Records)
+	}
+	if got, want := db.keys(), []string{"alice", "bob"}; !equal(got, want) {
+		t.Fatalf("keys = %v, want %v", got, want)
+	}
+}
+
+// TestLoadMsgpack loads a real concatenated msgpack stream through Load, not
+// just the decoder in isolation. One record's value contains a literal
+// newline byte, which a newline-framed reader would misframe; loadMsgpack
+// instead decodes self-delimited msgpack values straight off the stream, so
+// it must come through intact.
+func TestLoadMsgpack(t *testing.T) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	records := []map[string]interface{}{
+		{"name": "alice", "value": "line one\nline two"},
+		{"name": "bob", "value": "25"},
+	}
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db := newMemBackend()
+	stats, err := Load(context.Background(), db, &buf, LoadOptions{Format: FormatMsgpack, BatchSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Records != 2 {
+		t.Fatalf("stats.Records = %d, want 2", stats.Records)
+	}
+	if got, want := db.keys(), []string{"alice", "bob"}; !equal(got, want) {
+		t.Fatalf("keys = %v, want %v", got, want)
+	}
+	if v, _ := db.Get([]byte("alice")); string(v.Value) != "line one\nline two" {
+		t.Fatalf("alice value = %q, want %q", v.Value, "line one\nline two")
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
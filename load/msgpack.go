@@ -0,0 +1,70 @@
+package load
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/zerodivisi0n/kvdb/backend"
+)
+
+// loadMsgpack implements the msgpack pipeline. msgpack is a binary encoding
+// whose records can legitimately contain a literal newline byte, so unlike
+// loadLines it cannot split records on "\n" — instead it decodes a
+// concatenated stream of self-delimited msgpack values directly off r with a
+// single Decoder, which msgpack.v5 already supports. Decoding shares the
+// Decoder's reader state and so, like loadDelimited, runs on a single
+// goroutine that feeds a pool of writer goroutines batching db.Put calls.
+func loadMsgpack(ctx context.Context, db backend.Backend, r io.Reader, opts LoadOptions) (Stats, error) {
+	keyPath := strings.Split(opts.KeyField, ".")
+	valuePath := strings.Split(opts.ValueField, ".")
+
+	start := time.Now()
+	writerCh := make(chan backend.Record)
+	writersWg, totalRecords := startWriters(db, opts, writerCh)
+
+	dec := msgpack.NewDecoder(r)
+	var readErr error
+loop:
+	for {
+		var doc map[string]interface{}
+		if err := dec.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			readErr = fmt.Errorf("load: decoding msgpack record: %w", err)
+			break
+		}
+
+		keyVal, ok := digPath(doc, keyPath)
+		if !ok {
+			continue
+		}
+		valueVal, ok := digPath(doc, valuePath)
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("%v", keyVal)
+		value := fmt.Sprintf("%v", valueVal)
+		if key == "" || value == "" {
+			continue
+		}
+		if opts.ReverseKeys {
+			key = reverseString(key)
+		}
+
+		select {
+		case <-ctx.Done():
+			readErr = ctx.Err()
+			break loop
+		case writerCh <- backend.Record{Key: key, Value: []byte(value)}:
+		}
+	}
+
+	close(writerCh)
+	writersWg.Wait()
+
+	return Stats{Records: *totalRecords, Elapsed: time.Since(start)}, readErr
+}
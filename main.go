@@ -1,32 +1,23 @@
 package main
 
 import (
-	"bufio"
-	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
-	"sync"
-	"sync/atomic"
+	"syscall"
 	"time"
 
-	"github.com/buger/jsonparser"
+	"github.com/zerodivisi0n/kvdb/backend"
+	"github.com/zerodivisi0n/kvdb/load"
+	"github.com/zerodivisi0n/kvdb/query"
 )
 
-type Record struct {
-	Key   string
-	Value []byte
-}
-
-type Backend interface {
-	Put(records []Record) error
-	Search(prefix string) ([]Record, error)
-	Close() error
-}
-
 type JSONLine struct {
 	Query string `json:"query"`
 	Key   string `json:"key"`
@@ -34,57 +25,84 @@ type JSONLine struct {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		serveMain(os.Args[2:])
+		return
+	}
+
 	var (
 		dbName           string
 		backendType      string
+		addr             string
+		ttl              time.Duration
 		inputFilename    string
-		query            string
+		inputFormat      string
+		keyField         string
+		valueField       string
+		queryStr         string
+		queryType        string
 		queryConcurrency int
+		stream           bool
 		batchSize        int
 		jsonFmt          bool
 	)
-	flag.StringVar(&dbName, "db", "", "Database name")
-	flag.StringVar(&backendType, "backend", "badgerdb", "Database backend (leveldb, bbolt, badgerdb)")
+	flag.StringVar(&dbName, "db", "", "Database name (embedded backends)")
+	flag.StringVar(&backendType, "backend", "badgerdb", fmt.Sprintf("Database backend (%s)", strings.Join(backend.Registered(), ", ")))
+	flag.StringVar(&addr, "addr", "", "Remote backend address, host:port (memcached)")
+	flag.DurationVar(&ttl, "ttl", 0, "Record expiration for remote backends (0 = never expires)")
 	flag.StringVar(&inputFilename, "i", "", "Input filename")
-	flag.StringVar(&query, "q", "", "Comma-separated query string")
+	flag.StringVar(&inputFormat, "format", "jsonl", "Input format: jsonl, csv, tsv, or msgpack")
+	flag.StringVar(&keyField, "key-field", "name", "Key field: JSON path for jsonl/msgpack, column name/index for csv/tsv")
+	flag.StringVar(&valueField, "value-field", "value", "Value field: JSON path for jsonl/msgpack, column name/index for csv/tsv")
+	flag.StringVar(&queryStr, "q", "", "Comma-separated query string")
+	flag.StringVar(&queryType, "query-type", "suffix", "Query type: prefix, suffix, substring, or fuzzy (substring and fuzzy require -backend bleve)")
 	flag.IntVar(&queryConcurrency, "c", 10, "Query concurrency")
+	flag.BoolVar(&stream, "stream", false, "Print each result as it arrives instead of buffering and reordering by query; results are unordered")
 	flag.IntVar(&batchSize, "b", 5000, "Batch size")
 	flag.BoolVar(&jsonFmt, "json", false, "Print output as json")
 	flag.Parse()
 
-	if dbName == "" {
-		fmt.Fprintln(os.Stderr, "Missing db flag")
+	if dbName == "" && addr == "" {
+		fmt.Fprintln(os.Stderr, "Missing db or addr flag")
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	var backend Backend
-	var err error
-	switch backendType {
-	case "dummy":
-		backend = NewDummyBackend()
-	case "leveldb":
-		backend, err = NewLevelDBBackend(dbName + ".ldb")
-	case "bbolt":
-		backend, err = NewBBoltBackend(dbName + ".bbolt")
-	case "badgerdb":
-		backend, err = NewBadgerDBBackend(dbName + ".badger")
-	default:
-		err = fmt.Errorf("invalid backend")
-	}
+	db, err := backend.New(backendType, backend.Options{Name: dbName, Dir: dbName, Addr: addr, TTL: ttl})
 	if err != nil {
 		log.Panicf("Failed to open file: %v", err)
 	}
 
-	defer backend.Close()
+	defer db.Close()
 
 	if inputFilename != "" {
-		if err := loadFile(backend, inputFilename, batchSize); err != nil {
+		f, err := os.Open(inputFilename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reverseKeys := queryType == "suffix"
+		opts := load.LoadOptions{
+			Format:     load.Format(inputFormat),
+			KeyField:   keyField,
+			ValueField: valueField,
+			BatchSize:  batchSize,
+			// Keys are only reversed at ingest time when queries will be
+			// reversed too (the suffix-search trick); other query types
+			// need the key stored as-is.
+			ReverseKeys: reverseKeys,
+		}
+		stats, err := load.Load(context.Background(), db, f, opts)
+		f.Close()
+		if err != nil {
 			log.Fatal(err)
 		}
+		log.Printf("Loaded %d records in %v", stats.Records, stats.Elapsed)
+		if err := recordKeyLayout(db, reverseKeys); err != nil {
+			log.Fatalf("Failed to record key layout: %v", err)
+		}
 	}
 
-	queryParts := strings.Split(query, ",")
+	queryParts := strings.Split(queryStr, ",")
 	queryTerms := queryParts[:0]
 	for _, q := range queryParts {
 		if len(q) > 0 {
@@ -92,172 +110,147 @@ func main() {
 		}
 	}
 	if len(queryTerms) > 0 {
-		concurrency := queryConcurrency
-		if l := len(queryTerms); l < concurrency {
-			concurrency = l
+		if err := checkKeyLayout(db, queryType); err != nil {
+			log.Fatal(err)
 		}
-		type queryResult struct {
-			query   string
-			records []Record
-			err     error
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+
+		runner := query.Runner{
+			DB:          db,
+			Concurrency: queryConcurrency,
+			Query: func(db backend.Backend, term string) ([]backend.Record, error) {
+				return runQuery(db, term, queryType)
+			},
 		}
-		var results []queryResult
-		if concurrency == 1 {
-			for _, q := range queryTerms {
-				records, err := backend.Search(reverse(q))
-				results = append(results, queryResult{
-					query:   q,
-					records: records,
-					err:     err,
-				})
+		resultCh := runner.Run(ctx, queryTerms)
+
+		if stream {
+			for res := range resultCh {
+				printQueryResult(res, queryType, jsonFmt)
 			}
 		} else {
-			queryCh := make(chan string)
-			outCh := make(chan queryResult)
-			var wg sync.WaitGroup
-			for i := 0; i < concurrency; i++ {
-				wg.Add(1)
-				go func() {
-					for q := range queryCh {
-						records, err := backend.Search(reverse(q))
-						outCh <- queryResult{
-							query:   q,
-							records: records,
-							err:     err,
-						}
-					}
-					wg.Done()
-				}()
+			results := make([]query.Result, len(queryTerms))
+			for res := range resultCh {
+				results[res.Index] = res
 			}
-
-			go func() {
-				for res := range outCh {
-					results = append(results, res)
-				}
-			}()
-
-			for _, q := range queryTerms {
-				queryCh <- q
+			for _, res := range results {
+				printQueryResult(res, queryType, jsonFmt)
 			}
-			close(queryCh)
-			wg.Wait()
-			close(outCh)
 		}
-		for _, res := range results {
-			if res.err != nil {
-				log.Printf("Failed to search '%s': %v", res.query, res.err)
-				continue
-			}
-			for _, r := range res.records {
-				key := reverse(r.Key)
-				if !jsonFmt {
-					fmt.Printf("%s: %s\n", key, strings.TrimSpace(string(r.Value)))
-				} else {
-					output, err := json.Marshal(JSONLine{Query: res.query, Key: key, Value: string(r.Value)})
-					if err != nil {
-						log.Fatal(err)
-					}
-					fmt.Println(string(output))
-				}
+		if ctx.Err() != nil {
+			log.Printf("Query aborted: %v", ctx.Err())
+		}
+	}
+}
+
+// printQueryResult logs res.Err, if any, or otherwise prints every record it
+// carries in the CLI's plain or -json output format.
+func printQueryResult(res query.Result, queryType string, jsonFmt bool) {
+	if res.Err != nil {
+		log.Printf("Failed to search '%s': %v", res.Query, res.Err)
+		return
+	}
+	for _, r := range res.Records {
+		key := r.Key
+		if queryType == "suffix" {
+			key = reverse(key)
+		}
+		if !jsonFmt {
+			fmt.Printf("%s: %s\n", key, strings.TrimSpace(string(r.Value)))
+		} else {
+			output, err := json.Marshal(JSONLine{Query: res.Query, Key: key, Value: string(r.Value)})
+			if err != nil {
+				log.Fatal(err)
 			}
+			fmt.Println(string(output))
 		}
 	}
 }
 
-func loadFile(backend Backend, filename string, batchSize int) error {
-	f, err := os.Open(filename)
+// runQuery executes a single query term against db according to queryType.
+// prefix and suffix are supported by every backend's Search; substring and
+// fuzzy require a backend.Queryable backend such as bleve.
+func runQuery(db backend.Backend, term, queryType string) ([]backend.Record, error) {
+	switch queryType {
+	case "prefix":
+		return db.Search(term)
+	case "suffix":
+		return db.Search(reverse(term))
+	case "substring", "fuzzy":
+		q, ok := db.(backend.Queryable)
+		if !ok {
+			return nil, fmt.Errorf("backend does not support -query-type %s", queryType)
+		}
+		if queryType == "substring" {
+			return q.Query("*" + term + "*")
+		}
+		return q.Query(term + "~")
+	default:
+		return nil, fmt.Errorf("unknown -query-type %q", queryType)
+	}
+}
+
+// keyLayoutMetaKey is a reserved record key main uses to remember how keys
+// were stored by the most recent load, so a later query with a mismatched
+// -query-type fails with a clear error instead of silently finding nothing.
+const keyLayoutMetaKey = "__kvdb_key_layout__"
+
+// keyLayout is the value recorded under keyLayoutMetaKey.
+type keyLayout struct {
+	Reversed bool `json:"reversed"`
+}
+
+// recordKeyLayout persists whether -i just stored keys reversed (the
+// suffix-search trick), so a later checkKeyLayout call can catch a
+// -query-type that doesn't match how this db's keys are actually stored.
+func recordKeyLayout(db backend.Backend, reversed bool) error {
+	data, err := json.Marshal(keyLayout{Reversed: reversed})
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	return db.Put([]backend.Record{{Key: keyLayoutMetaKey, Value: data}})
+}
 
-	gr, err := gzip.NewReader(f)
+// checkKeyLayout compares queryType's key-layout requirement ("suffix"
+// expects reversed keys, everything else expects keys as-is) against the
+// layout recorded by the most recent -i load, returning an error on
+// mismatch. A db with no recorded layout (never loaded by this kvdb, or
+// loaded before this check existed) is not validated.
+func checkKeyLayout(db backend.Backend, queryType string) error {
+	rec, err := db.Get([]byte(keyLayoutMetaKey))
+	if errors.Is(err, backend.ErrNotFound) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
-	defer gr.Close()
-
-	const (
-		parsersCount = 12
-		writersCount = 4
-	)
-
-	start := time.Now()
-	parserCh := make(chan []byte)
-	writerCh := make(chan Record)
-
-	var parsersWg sync.WaitGroup
-	for i := 0; i < parsersCount; i++ {
-		parsersWg.Add(1)
-		go func() {
-			for data := range parserCh {
-				var key, value []byte
-				keys := [][]string{
-					[]string{"name"},
-					[]string{"value"},
-				}
-				jsonparser.EachKey(data, func(idx int, val []byte, _ jsonparser.ValueType, err error) {
-					if err != nil {
-						log.Printf("parse index %d error: %v", idx, err)
-						return
-					}
-					if idx == 0 {
-						key = val
-					} else if idx == 1 {
-						value = val
-					}
-				}, keys...)
-				if len(key) > 0 && len(value) > 0 {
-					writerCh <- Record{
-						Key:   reverse(string(key)),
-						Value: copyBytes(value),
-					}
-				}
-			}
-			parsersWg.Done()
-		}()
+	var layout keyLayout
+	if err := json.Unmarshal(rec.Value, &layout); err != nil {
+		return err
 	}
-
-	var writersWg sync.WaitGroup
-	var totalRecords int64
-	for i := 0; i < writersCount; i++ {
-		writersWg.Add(1)
-		go func() {
-			records := make([]Record, 0, batchSize)
-			for r := range writerCh {
-				records = append(records, r)
-				if len(records) == batchSize {
-					if err := backend.Put(records); err != nil {
-						log.Printf("Failed to put records: %v", err)
-					}
-					records = records[:0]
-				}
-				if cnt := atomic.AddInt64(&totalRecords, 1); cnt%1000000 == 0 {
-					since := time.Since(start)
-					log.Printf("Put %d records in %v (%.2f rps)", cnt, since, float64(cnt)/since.Seconds())
-				}
-			}
-			if len(records) > 0 {
-				if err := backend.Put(records); err != nil {
-					log.Printf("Failed to put records: %v", err)
-				}
-			}
-			writersWg.Done()
-		}()
+	wantReversed := queryType == "suffix"
+	if layout.Reversed != wantReversed {
+		return fmt.Errorf("-query-type %q expects keys stored %s, but this db was loaded with reversed keys=%v; reload with -query-type %q or query with the -query-type used at load time",
+			queryType, reversedDesc(wantReversed), layout.Reversed, queryTypeFor(layout.Reversed))
 	}
+	return nil
+}
 
-	scanner := bufio.NewScanner(gr)
-	log.Printf("Start loading file")
-	for scanner.Scan() {
-		parserCh <- copyBytes(scanner.Bytes())
+// reversedDesc renders a reversed flag for the checkKeyLayout error message.
+func reversedDesc(reversed bool) string {
+	if reversed {
+		return "reversed"
 	}
+	return "as-is"
+}
 
-	close(parserCh)
-	parsersWg.Wait()
-
-	close(writerCh)
-	writersWg.Wait()
-
-	log.Printf("Loaded %d records in %v", totalRecords, time.Since(start))
-	return scanner.Err()
+// queryTypeFor returns a -query-type matching a recorded key layout, for the
+// checkKeyLayout error message.
+func queryTypeFor(reversed bool) string {
+	if reversed {
+		return "suffix"
+	}
+	return "prefix"
 }
@@ -0,0 +1,89 @@
+// Package query runs a batch of query terms against a backend.Backend
+// concurrently, returning results on a channel so callers can stream them as
+// they arrive or collect and reorder them by query index.
+package query
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zerodivisi0n/kvdb/backend"
+)
+
+// Result is one query's outcome. Index is the term's position in the slice
+// passed to Run, so callers that need query order can reorder results
+// arriving out of order from concurrent workers.
+type Result struct {
+	Index   int
+	Query   string
+	Records []backend.Record
+	Err     error
+}
+
+// Func executes a single query term against db. Callers supply this to
+// dispatch on query type (prefix, suffix, substring, fuzzy, ...).
+type Func func(db backend.Backend, term string) ([]backend.Record, error)
+
+// Runner runs a batch of query terms against DB concurrently using Query.
+type Runner struct {
+	DB          backend.Backend
+	Concurrency int
+	Query       Func
+}
+
+// Run spawns a pool of Concurrency workers over queries and returns a
+// channel of Results, one per query, carrying its original index. Results
+// arrive in completion order, not query order; callers that need ordered
+// output should collect and sort by Index, or print immediately for a
+// streaming, unordered view. The returned channel is closed once every
+// query has completed or ctx is canceled.
+func (r *Runner) Run(ctx context.Context, queries []string) <-chan Result {
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if concurrency > len(queries) {
+		concurrency = len(queries)
+	}
+
+	type job struct {
+		index int
+		query string
+	}
+	jobCh := make(chan job)
+	outCh := make(chan Result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobCh {
+				records, err := r.Query(r.DB, j.query)
+				select {
+				case outCh <- Result{Index: j.index, Query: j.query, Records: records, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobCh)
+		for i, q := range queries {
+			select {
+			case jobCh <- job{index: i, query: q}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(outCh)
+	}()
+
+	return outCh
+}
@@ -0,0 +1,76 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zerodivisi0n/kvdb/backend"
+)
+
+// TestRunnerOrdering checks that collecting Results by Index recovers query
+// order even though workers complete out of order.
+func TestRunnerOrdering(t *testing.T) {
+	queries := make([]string, 50)
+	for i := range queries {
+		queries[i] = fmt.Sprintf("q%d", i)
+	}
+
+	r := &Runner{
+		Concurrency: 8,
+		Query: func(db backend.Backend, term string) ([]backend.Record, error) {
+			// Vary completion order across workers.
+			time.Sleep(time.Duration(len(term)%3) * time.Millisecond)
+			return []backend.Record{{Key: term}}, nil
+		},
+	}
+
+	results := make([]Result, len(queries))
+	for res := range r.Run(context.Background(), queries) {
+		results[res.Index] = res
+	}
+
+	for i, res := range results {
+		want := queries[i]
+		if res.Query != want {
+			t.Fatalf("results[%d].Query = %q, want %q", i, res.Query, want)
+		}
+		if len(res.Records) != 1 || res.Records[0].Key != want {
+			t.Fatalf("results[%d].Records = %v, want [{%q}]", i, res.Records, want)
+		}
+	}
+}
+
+// TestRunnerCancellation checks that canceling ctx stops dispatch promptly
+// instead of running every query to completion.
+func TestRunnerCancellation(t *testing.T) {
+	queries := make([]string, 1000)
+	for i := range queries {
+		queries[i] = fmt.Sprintf("q%d", i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{}, len(queries))
+	r := &Runner{
+		Concurrency: 4,
+		Query: func(db backend.Backend, term string) ([]backend.Record, error) {
+			started <- struct{}{}
+			time.Sleep(time.Millisecond)
+			return nil, nil
+		},
+	}
+
+	outCh := r.Run(ctx, queries)
+	<-started
+	cancel()
+
+	for range outCh {
+		// Drain until the runner closes outCh; this must happen even
+		// though not every query ran.
+	}
+
+	if len(started) >= len(queries) {
+		t.Fatalf("cancellation did not stop dispatch: %d/%d queries started", len(started), len(queries))
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/zerodivisi0n/kvdb/backend"
+	"github.com/zerodivisi0n/kvdb/load"
+)
+
+// serveMain implements the "kvdb serve" subcommand: it keeps a single
+// backend open and serves queries and bulk ingest over HTTP instead
+// of the one-shot open/query/close cycle of the default command, which is
+// expensive and locks the on-disk store to a single process.
+func serveMain(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	var (
+		addr        string
+		dbName      string
+		backendType string
+		backendAddr string
+		backendTTL  time.Duration
+		queryType   string
+		batchSize   int
+	)
+	fs.StringVar(&addr, "addr", ":7000", "Listen address")
+	fs.StringVar(&dbName, "db", "", "Database name (embedded backends)")
+	fs.StringVar(&backendType, "backend", "badgerdb", fmt.Sprintf("Database backend (%s)", strings.Join(backend.Registered(), ", ")))
+	fs.StringVar(&backendAddr, "backend-addr", "", "Remote backend address, host:port (memcached)")
+	fs.DurationVar(&backendTTL, "ttl", 0, "Record expiration for remote backends (0 = never expires)")
+	fs.StringVar(&queryType, "query-type", "suffix", "Query type: prefix, suffix, substring, or fuzzy (substring and fuzzy require -backend bleve)")
+	fs.IntVar(&batchSize, "b", 5000, "Batch size for /put ingest")
+	fs.Parse(args)
+
+	if dbName == "" && backendAddr == "" {
+		fmt.Fprintln(os.Stderr, "Missing db or backend-addr flag")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	db, err := backend.New(backendType, backend.Options{Name: dbName, Dir: dbName, Addr: backendAddr, TTL: backendTTL})
+	if err != nil {
+		log.Panicf("Failed to open file: %v", err)
+	}
+	defer db.Close()
+
+	srv := &server{db: db, batchSize: batchSize, queryType: queryType}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", srv.handleSearch)
+	mux.HandleFunc("/put", srv.handlePut)
+
+	httpServer := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	go func() {
+		log.Printf("Listening on %s", addr)
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Serve failed: %v", err)
+		}
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	<-sigCh
+
+	log.Printf("Shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Printf("Graceful shutdown failed: %v", err)
+	}
+}
+
+// server holds the long-lived backend shared across requests.
+type server struct {
+	db        backend.Backend
+	batchSize int
+
+	// queryType is the -query-type the daemon was started with; see
+	// runQuery for the supported values and their backend requirements.
+	queryType string
+}
+
+// handleSearch serves GET /search?q=foo,bar&limit=100, streaming matching
+// records as newline-delimited JSON as they are found.
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	terms := strings.Split(r.URL.Query().Get("q"), ",")
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+
+	count := 0
+	for _, term := range terms {
+		if term == "" {
+			continue
+		}
+		records, err := runQuery(s.db, term, s.queryType)
+		if err != nil {
+			log.Printf("Failed to search %q: %v", term, err)
+			continue
+		}
+		for _, rec := range records {
+			if limit > 0 && count >= limit {
+				return
+			}
+			key := rec.Key
+			if s.queryType == "suffix" {
+				key = reverse(key)
+			}
+			line := JSONLine{Query: term, Key: key, Value: string(rec.Value)}
+			if err := enc.Encode(line); err != nil {
+				return
+			}
+			count++
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// handlePut serves POST /put: a bulk-ingest endpoint that accepts
+// newline-delimited {"name","value"} records, transparently gzip-decoded,
+// and feeds them through the same parser/writer pipeline as the CLI's -i
+// flag.
+func (s *server) handlePut(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	opts := load.LoadOptions{BatchSize: s.batchSize, ReverseKeys: s.queryType == "suffix"}
+	if _, err := load.Load(r.Context(), s.db, r.Body, opts); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}